@@ -0,0 +1,225 @@
+// Copyright 2021 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	nats "github.com/nats-io/nats-server/v2/server"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	varzSuffix   = "/varz"
+	routezSuffix = "/routez"
+
+	// discoveryRequestTimeout caps a single /varz or /routez fetch made
+	// while discovering the rest of the cluster.
+	discoveryRequestTimeout = 5 * time.Second
+
+	// defaultDiscoverInterval is used when --discover-interval is unset.
+	defaultDiscoverInterval = 30 * time.Second
+)
+
+// MonitorURLTemplate turns the host a server gossips in its cluster routes
+// into that server's monitoring URL. Monitor ports are never gossiped, so
+// callers must supply this mapping, e.g. "every server listens on :8222".
+type MonitorURLTemplate func(host string) string
+
+// ServerSink receives the current set of known servers whenever cluster
+// discovery learns of a change. jszCollector implements it.
+type ServerSink interface {
+	SetServers(servers []*CollectedServer)
+}
+
+// Discoverer periodically polls a seed server's /varz and /routez, learns
+// the rest of the cluster from the gossiped route table, and pushes the
+// resulting server list to every registered sink, so a growing or shrinking
+// cluster doesn't require hand-editing the exporter's server list or
+// restarting it.
+type Discoverer struct {
+	httpClient *http.Client
+	seedURL    string
+	monitorURL MonitorURLTemplate
+	interval   time.Duration
+
+	mu      sync.Mutex
+	servers map[string]*CollectedServer
+	sinks   []ServerSink
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	discovered prometheus.Gauge
+}
+
+// NewDiscoverer creates a Discoverer that starts from seedURL (a server's
+// monitoring URL, e.g. "http://localhost:8222") and maps every other
+// gossiped cluster member's route host to a monitoring URL via monitorURL.
+// interval defaults to 30s when zero.
+func NewDiscoverer(seedURL string, monitorURL MonitorURLTemplate, interval time.Duration) *Discoverer {
+	if interval <= 0 {
+		interval = defaultDiscoverInterval
+	}
+
+	return &Discoverer{
+		httpClient: &http.Client{Timeout: discoveryRequestTimeout},
+		seedURL:    seedURL,
+		monitorURL: monitorURL,
+		interval:   interval,
+		servers:    make(map[string]*CollectedServer),
+		discovered: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "nats_exporter_discovered_servers",
+			Help: "Number of NATS servers currently known to the exporter via cluster discovery",
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector, so nats_exporter_discovered_servers
+// is exposed alongside the jsz/varz/connz families.
+func (d *Discoverer) Describe(ch chan<- *prometheus.Desc) {
+	d.discovered.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (d *Discoverer) Collect(ch chan<- prometheus.Metric) {
+	d.discovered.Collect(ch)
+}
+
+// AddSink registers a collector to receive updated server lists whenever
+// cluster membership changes. Sinks added after discovery has already run
+// are backfilled with the current server list.
+func (d *Discoverer) AddSink(sink ServerSink) {
+	d.mu.Lock()
+	d.sinks = append(d.sinks, sink)
+	servers := d.serverListLocked()
+	d.mu.Unlock()
+
+	if len(servers) > 0 {
+		sink.SetServers(servers)
+	}
+}
+
+// Start begins polling every interval, in the background, until Stop is
+// called. Start is a no-op if already running.
+func (d *Discoverer) Start(ctx context.Context) {
+	d.mu.Lock()
+	if d.stopCh != nil {
+		d.mu.Unlock()
+		return
+	}
+	d.stopCh = make(chan struct{})
+	d.doneCh = make(chan struct{})
+	stopCh, doneCh := d.stopCh, d.doneCh
+	d.mu.Unlock()
+
+	go d.run(ctx, stopCh, doneCh)
+}
+
+// Stop halts polling and waits for any discovery in progress to finish.
+// Stop is a no-op if not running.
+func (d *Discoverer) Stop() {
+	d.mu.Lock()
+	stopCh, doneCh := d.stopCh, d.doneCh
+	d.stopCh, d.doneCh = nil, nil
+	d.mu.Unlock()
+
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	<-doneCh
+}
+
+func (d *Discoverer) run(ctx context.Context, stopCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	if err := d.Discover(ctx); err != nil {
+		Debugf("discovery: %v", err)
+	}
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := d.Discover(ctx); err != nil {
+				Debugf("discovery: %v", err)
+			}
+		}
+	}
+}
+
+// Discover fetches /varz and /routez from the seed server, folds in every
+// gossiped route, and pushes the resulting server list to every registered
+// sink. It can be called directly (e.g. from tests) without Start/Stop.
+func (d *Discoverer) Discover(ctx context.Context) error {
+	var varz nats.Varz
+	if err := getMetricURLWithContext(ctx, d.httpClient, d.seedURL+varzSuffix, &varz); err != nil {
+		return fmt.Errorf("discovery: fetching seed varz: %w", err)
+	}
+
+	var routez nats.Routez
+	if err := getMetricURLWithContext(ctx, d.httpClient, d.seedURL+routezSuffix, &routez); err != nil {
+		return fmt.Errorf("discovery: fetching seed routez: %w", err)
+	}
+
+	// Rebuild the server set from scratch each round, keyed by the stable
+	// server_id so Prometheus series don't churn as the cluster is repolled,
+	// but dropping any peer no longer gossiped in routez so a decommissioned
+	// server stops being polled and reported instead of lingering forever.
+	// The seed is always kept, since it isn't gossiped about itself.
+	updated := make(map[string]*CollectedServer, len(routez.Routes)+1)
+	updated[varz.ID] = &CollectedServer{ID: varz.ID, URL: d.seedURL}
+	for _, route := range routez.Routes {
+		if route.RemoteID == "" || route.IP == "" {
+			continue
+		}
+		if _, ok := updated[route.RemoteID]; ok {
+			continue
+		}
+		updated[route.RemoteID] = &CollectedServer{
+			ID:  route.RemoteID,
+			URL: d.monitorURL(route.IP),
+		}
+	}
+
+	d.mu.Lock()
+	d.servers = updated
+	servers := d.serverListLocked()
+	sinks := make([]ServerSink, len(d.sinks))
+	copy(sinks, d.sinks)
+	d.mu.Unlock()
+
+	d.discovered.Set(float64(len(servers)))
+	for _, sink := range sinks {
+		sink.SetServers(servers)
+	}
+	return nil
+}
+
+// serverListLocked snapshots the known servers. Callers must hold d.mu.
+func (d *Discoverer) serverListLocked() []*CollectedServer {
+	servers := make([]*CollectedServer, 0, len(d.servers))
+	for _, s := range d.servers {
+		servers = append(servers, s)
+	}
+	return servers
+}
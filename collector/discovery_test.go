@@ -0,0 +1,145 @@
+// Copyright 2021 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats-server/v2/test"
+)
+
+// fakeSink records every server list handed to it by a Discoverer.
+type fakeSink struct {
+	updates [][]*CollectedServer
+}
+
+func (f *fakeSink) SetServers(servers []*CollectedServer) {
+	f.updates = append(f.updates, servers)
+}
+
+func runClusterNode(t *testing.T, name string, routePort int) (*natsserver.Server, int) {
+	t.Helper()
+
+	opts := test.DefaultTestOptions
+	opts.ServerName = name
+	opts.Port = -1
+	opts.Cluster.Port = -1
+	opts.Cluster.Host = "127.0.0.1"
+	opts.HTTPHost = "127.0.0.1"
+	opts.HTTPPort = -1
+	if routePort != 0 {
+		routeURL, err := url.Parse(fmt.Sprintf("nats-route://127.0.0.1:%d", routePort))
+		if err != nil {
+			t.Fatalf("failed to build route URL: %v", err)
+		}
+		opts.Routes = []*url.URL{routeURL}
+	}
+
+	srv := test.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+
+	return srv, srv.MonitorAddr().Port
+}
+
+func TestDiscovererLearnsClusterFromRoutez(t *testing.T) {
+	seedCluster, seedMonitorPort := runClusterNode(t, "seed", 0)
+	_, peerMonitorPort := runClusterNode(t, "peer", seedCluster.ClusterAddr().Port)
+
+	// Give the route a moment to form before asking /routez about it.
+	for i := 0; i < 200 && seedCluster.NumRoutes() == 0; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	monitorPorts := map[string]int{
+		"127.0.0.1": peerMonitorPort,
+	}
+	d := NewDiscoverer(
+		fmt.Sprintf("http://127.0.0.1:%d", seedMonitorPort),
+		func(host string) string {
+			return fmt.Sprintf("http://%s:%d", host, monitorPorts[host])
+		},
+		time.Minute,
+	)
+
+	sink := &fakeSink{}
+	d.AddSink(sink)
+
+	if err := d.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	if len(sink.updates) == 0 {
+		t.Fatal("expected the sink to receive at least one update")
+	}
+	servers := sink.updates[len(sink.updates)-1]
+	if len(servers) != 2 {
+		t.Fatalf("expected 2 discovered servers (seed + peer), got %d", len(servers))
+	}
+}
+
+// TestDiscovererPrunesDepartedServers asserts that a server which stops
+// showing up in the seed's /routez (e.g. it was decommissioned) is dropped
+// from the discovered set, rather than being polled and reported forever;
+// the seed itself is never pruned, since it isn't gossiped about itself.
+func TestDiscovererPrunesDepartedServers(t *testing.T) {
+	seedCluster, seedMonitorPort := runClusterNode(t, "seed", 0)
+	peer, peerMonitorPort := runClusterNode(t, "peer", seedCluster.ClusterAddr().Port)
+
+	for i := 0; i < 200 && seedCluster.NumRoutes() == 0; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	monitorPorts := map[string]int{
+		"127.0.0.1": peerMonitorPort,
+	}
+	d := NewDiscoverer(
+		fmt.Sprintf("http://127.0.0.1:%d", seedMonitorPort),
+		func(host string) string {
+			return fmt.Sprintf("http://%s:%d", host, monitorPorts[host])
+		},
+		time.Minute,
+	)
+
+	sink := &fakeSink{}
+	d.AddSink(sink)
+
+	if err := d.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if got := len(sink.updates[len(sink.updates)-1]); got != 2 {
+		t.Fatalf("expected 2 discovered servers (seed + peer) before shrink, got %d", got)
+	}
+
+	peer.Shutdown()
+	for i := 0; i < 200 && seedCluster.NumRoutes() != 0; i++ {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if err := d.Discover(context.Background()); err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	servers := sink.updates[len(sink.updates)-1]
+	if len(servers) != 1 {
+		t.Fatalf("expected only the seed to remain after the peer left, got %d servers: %+v", len(servers), servers)
+	}
+	if servers[0].ID != seedCluster.ID() {
+		t.Fatalf("expected the surviving server to be the seed, got %+v", servers[0])
+	}
+}
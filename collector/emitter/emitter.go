@@ -0,0 +1,216 @@
+// Copyright 2021 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emitter publishes collected metric samples as CloudEvents over
+// NATS, as an alternative (or complement) to the pull-based /metrics scrape
+// endpoint for deployments where Prometheus cannot reach the exporter.
+package emitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nuid"
+)
+
+const (
+	// specVersion is the CloudEvents spec version emitted events conform to.
+	specVersion = "1.0"
+
+	// typePrefix namespaces every ce-type emitted by this package, e.g.
+	// "io.nats.exporter.jsz.stream".
+	typePrefix = "io.nats.exporter."
+
+	// dataContentType is the media type of the Data field of every event.
+	dataContentType = "application/json"
+)
+
+// Sample is a single collected metric value, modeled after a Prometheus
+// metric family member: a name, its label set, and its current value.
+type Sample struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// Event is a CloudEvents-formatted envelope around a Sample.
+type Event struct {
+	SpecVersion     string `json:"specversion"`
+	Type            string `json:"type"`
+	Source          string `json:"source"`
+	ID              string `json:"id"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            Sample `json:"data"`
+}
+
+// Transport publishes already-encoded event payloads to a subject. Core NATS
+// and JetStream publishing are both supported via separate implementations.
+type Transport interface {
+	// Publish sends data to subject, blocking until it is accepted (and, for
+	// a JetStream transport, acknowledged).
+	Publish(subject string, data []byte) error
+
+	// Close releases any resources held by the transport.
+	Close() error
+}
+
+// Source produces a snapshot of samples to emit, e.g. the current jsz/varz
+// family values. It is called once per flush interval.
+type Source func() ([]Sample, error)
+
+// Config configures an Emitter.
+type Config struct {
+	// Transport is the publisher used to send events; required.
+	Transport Transport
+
+	// Source produces the samples to emit on each flush; required.
+	Source Source
+
+	// Subject is the subject every event is published to; required.
+	Subject string
+
+	// InstanceID identifies this exporter instance and is used as the
+	// CloudEvents ce-source of every event; required.
+	InstanceID string
+
+	// FlushInterval is how often Source is polled and its samples emitted.
+	// Defaults to 10s when zero.
+	FlushInterval time.Duration
+}
+
+// Emitter periodically takes a snapshot from a Source and publishes each
+// sample as a CloudEvents JSON message via a Transport.
+type Emitter struct {
+	transport     Transport
+	source        Source
+	subject       string
+	instanceID    string
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+	running bool
+}
+
+// NewEmitter creates an Emitter from cfg. It does not start polling until
+// Start is called.
+func NewEmitter(cfg Config) (*Emitter, error) {
+	if cfg.Transport == nil {
+		return nil, fmt.Errorf("emitter: Transport is required")
+	}
+	if cfg.Source == nil {
+		return nil, fmt.Errorf("emitter: Source is required")
+	}
+	if cfg.Subject == "" {
+		return nil, fmt.Errorf("emitter: Subject is required")
+	}
+	if cfg.InstanceID == "" {
+		return nil, fmt.Errorf("emitter: InstanceID is required")
+	}
+
+	flushInterval := cfg.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = 10 * time.Second
+	}
+
+	return &Emitter{
+		transport:     cfg.Transport,
+		source:        cfg.Source,
+		subject:       cfg.Subject,
+		instanceID:    cfg.InstanceID,
+		flushInterval: flushInterval,
+	}, nil
+}
+
+// Start begins polling the Source every FlushInterval and publishing the
+// resulting samples until Stop is called. Start is a no-op if already
+// running.
+func (e *Emitter) Start() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.running {
+		return
+	}
+	e.running = true
+	e.stopCh = make(chan struct{})
+	e.doneCh = make(chan struct{})
+
+	go e.run(e.stopCh, e.doneCh)
+}
+
+// Stop halts polling and waits for the current flush, if any, to finish.
+// Stop is a no-op if not running.
+func (e *Emitter) Stop() {
+	e.mu.Lock()
+	if !e.running {
+		e.mu.Unlock()
+		return
+	}
+	e.running = false
+	stopCh, doneCh := e.stopCh, e.doneCh
+	e.mu.Unlock()
+
+	close(stopCh)
+	<-doneCh
+}
+
+func (e *Emitter) run(stopCh, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	ticker := time.NewTicker(e.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := e.Flush(); err != nil {
+				log.Printf("emitter: flush error: %v", err)
+			}
+		}
+	}
+}
+
+// Flush polls the Source once and publishes each sample as a CloudEvent. It
+// can be called directly (e.g. from tests) without Start/Stop.
+func (e *Emitter) Flush() error {
+	samples, err := e.source()
+	if err != nil {
+		return err
+	}
+
+	for _, sample := range samples {
+		data, err := json.Marshal(Event{
+			SpecVersion:     specVersion,
+			Type:            typePrefix + sample.Name,
+			Source:          e.instanceID,
+			ID:              nuid.Next(),
+			Time:            time.Now().UTC().Format(time.RFC3339Nano),
+			DataContentType: dataContentType,
+			Data:            sample,
+		})
+		if err != nil {
+			return err
+		}
+		if err := e.transport.Publish(e.subject, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats-server/v2/test"
+	nats "github.com/nats-io/nats.go"
+)
+
+func runEmbeddedServer(t *testing.T) (*natsserver.Server, *nats.Conn) {
+	t.Helper()
+
+	opts := test.DefaultTestOptions
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	srv := test.RunServer(&opts)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		srv.Shutdown()
+		t.Fatalf("failed to connect to embedded server: %v", err)
+	}
+
+	return srv, nc
+}
+
+func TestEmitterPublishesCloudEventsOverCoreNats(t *testing.T) {
+	srv, nc := runEmbeddedServer(t)
+	defer srv.Shutdown()
+	defer nc.Close()
+
+	sub, err := nc.SubscribeSync("exporter.metrics")
+	if err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+
+	e, err := NewEmitter(Config{
+		Transport:  NewCoreTransport(nc),
+		Subject:    "exporter.metrics",
+		InstanceID: "exporter-1",
+		Source: func() ([]Sample, error) {
+			return []Sample{
+				{Name: "jsz.stream.total_messages", Labels: map[string]string{"stream_name": "orders"}, Value: 42},
+			}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+
+	if err := e.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("did not receive published event: %v", err)
+	}
+
+	var evt Event
+	if err := json.Unmarshal(msg.Data, &evt); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+
+	if evt.Type != typePrefix+"jsz.stream.total_messages" {
+		t.Fatalf("unexpected ce-type: %s", evt.Type)
+	}
+	if evt.Source != "exporter-1" {
+		t.Fatalf("unexpected ce-source: %s", evt.Source)
+	}
+	if evt.ID == "" {
+		t.Fatal("expected a non-empty ce-id")
+	}
+	if evt.Data.Value != 42 {
+		t.Fatalf("unexpected sample value: %v", evt.Data.Value)
+	}
+}
+
+func TestEmitterPublishesOverJetStream(t *testing.T) {
+	srv, nc := runEmbeddedServer(t)
+	defer srv.Shutdown()
+	defer nc.Close()
+
+	js, err := nc.JetStream()
+	if err != nil {
+		t.Fatalf("JetStream context failed: %v", err)
+	}
+	if _, err := js.AddStream(&nats.StreamConfig{
+		Name:     "EXPORTER",
+		Subjects: []string{"exporter.>"},
+	}); err != nil {
+		t.Fatalf("AddStream failed: %v", err)
+	}
+
+	e, err := NewEmitter(Config{
+		Transport:  NewJetStreamTransport(js),
+		Subject:    "exporter.metrics",
+		InstanceID: "exporter-1",
+		Source: func() ([]Sample, error) {
+			return []Sample{{Name: "jsz.server.total_streams", Value: 3}}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewEmitter failed: %v", err)
+	}
+
+	if err := e.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	info, err := js.StreamInfo("EXPORTER")
+	if err != nil {
+		t.Fatalf("StreamInfo failed: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Fatalf("expected 1 acknowledged message in the stream, got %d", info.State.Msgs)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emitter
+
+import (
+	nats "github.com/nats-io/nats.go"
+)
+
+// CoreTransport publishes events with a core NATS publish: fire-and-forget,
+// Publish returns once the message is queued on the connection, with no
+// confirmation that the server received it.
+type CoreTransport struct {
+	nc *nats.Conn
+}
+
+// NewCoreTransport wraps an existing NATS connection as a Transport. The
+// caller remains responsible for closing nc; Close is a no-op.
+func NewCoreTransport(nc *nats.Conn) *CoreTransport {
+	return &CoreTransport{nc: nc}
+}
+
+// Publish implements Transport.
+func (t *CoreTransport) Publish(subject string, data []byte) error {
+	return t.nc.Publish(subject, data)
+}
+
+// Close implements Transport. The underlying connection is left open since
+// NewCoreTransport does not own it.
+func (t *CoreTransport) Close() error {
+	return nil
+}
+
+// JetStreamTransport publishes events through a JetStream context, so each
+// publish is acknowledged by the stream before Publish returns.
+type JetStreamTransport struct {
+	js nats.JetStreamContext
+}
+
+// NewJetStreamTransport wraps an existing JetStream context as a Transport.
+func NewJetStreamTransport(js nats.JetStreamContext) *JetStreamTransport {
+	return &JetStreamTransport{js: js}
+}
+
+// Publish implements Transport, waiting for the stream to ack the message.
+func (t *JetStreamTransport) Publish(subject string, data []byte) error {
+	_, err := t.js.Publish(subject, data)
+	return err
+}
+
+// Close implements Transport. The underlying connection is left open since
+// NewJetStreamTransport does not own it.
+func (t *JetStreamTransport) Close() error {
+	return nil
+}
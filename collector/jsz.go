@@ -15,16 +15,27 @@
 package collector
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"sync"
 	"time"
 
 	nats "github.com/nats-io/nats-server/v2/server"
 	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
 	jszSuffix = "/jsz?consumers=true"
+
+	// jszMaxConcurrentRequests bounds how many servers are polled at once
+	// per Collect call, so a large fleet does not open unbounded sockets.
+	jszMaxConcurrentRequests = 10
+
+	// jszRequestTimeout caps how long a single server's /jsz fetch may take,
+	// independent of how long the overall scrape has been running.
+	jszRequestTimeout = 5 * time.Second
 )
 
 type jszCollector struct {
@@ -33,21 +44,54 @@ type jszCollector struct {
 	servers    []*CollectedServer
 	system     string
 
+	// ctx is the context for the in-flight scrape, set by SetContext before
+	// Collect runs so server fetches are canceled if the HTTP request that
+	// triggered the scrape is canceled.
+	ctx context.Context
+
+	// group deduplicates concurrent fetches of the same server URL, so
+	// overlapping scrapes (e.g. Prometheus plus a manual curl) share one
+	// in-flight request instead of hammering the server twice.
+	group singleflight.Group
+
+	// seqValueType is the prometheus.ValueType used for the monotonically
+	// increasing sequence metrics (stream/consumer sequences, redelivery
+	// counts). It is GaugeValue when legacyJszGauges is set, for one release,
+	// so existing dashboards built on the old semantics don't break; it is
+	// CounterValue otherwise, so rate() works safely across restarts.
+	seqValueType prometheus.ValueType
+
 	// JetStream server stats
 	streams   *prometheus.Desc
 	consumers *prometheus.Desc
 	messages  *prometheus.Desc
 	bytes     *prometheus.Desc
 
+	// JetStream server API stats
+	apiTotal    *prometheus.Desc
+	apiErrors   *prometheus.Desc
+	apiInflight *prometheus.Desc
+
+	// JetStream per-account API stats
+	accountAPITotal    *prometheus.Desc
+	accountAPIErrors   *prometheus.Desc
+	accountAPIInflight *prometheus.Desc
+
 	// Stream stats
-	streamMessages      *prometheus.Desc
-	streamBytes         *prometheus.Desc
-	streamLastSeq       *prometheus.Desc
-	streamConsumerCount *prometheus.Desc
+	streamMessages        *prometheus.Desc
+	streamBytes           *prometheus.Desc
+	streamFirstSeq        *prometheus.Desc
+	streamLastSeq         *prometheus.Desc
+	streamConsumerCount   *prometheus.Desc
+	streamDeletedMessages *prometheus.Desc
+	streamNumSubjects     *prometheus.Desc
 
 	// Consumer stats
 	consumerDeliveredConsumerSeq *prometheus.Desc
 	consumerDeliveredStreamSeq   *prometheus.Desc
+	consumerAckFloorConsumerSeq  *prometheus.Desc
+	consumerAckFloorStreamSeq    *prometheus.Desc
+	consumerAckLag               *prometheus.Desc
 	consumerNumAckPending        *prometheus.Desc
 	consumerNumRedelivered       *prometheus.Desc
 	consumerNumWaiting           *prometheus.Desc
@@ -58,7 +102,29 @@ func isJszEndpoint(system, endpoint string) bool {
 	return system == JetStreamSystem
 }
 
-func newJszCollector(system, endpoint string, servers []*CollectedServer) prometheus.Collector {
+// getMetricURLWithContext behaves like getMetricURL but binds the request to
+// ctx, so a canceled or timed-out scrape aborts the in-flight HTTP round-trip
+// instead of leaking it.
+func getMetricURLWithContext(ctx context.Context, httpClient *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// newJszCollector builds the JetStream jsz collector. legacyJszGauges
+// preserves the pre-counter GaugeValue semantics of the sequence metrics
+// (--legacy-jsz-gauges) for one release, so dashboards built before rate()
+// became safe to use don't break outright.
+func newJszCollector(system, endpoint string, servers []*CollectedServer, legacyJszGauges bool) prometheus.Collector {
 	serverLabels := []string{"server_id", "cluster", "domain", "meta_leader"}
 
 	var streamLabels []string
@@ -72,9 +138,20 @@ func newJszCollector(system, endpoint string, servers []*CollectedServer) promet
 	consumerLabels = append(consumerLabels, "consumer_leader")
 	consumerLabels = append(consumerLabels, "deliver_subject")
 
+	var accountLabels []string
+	accountLabels = append(accountLabels, serverLabels...)
+	accountLabels = append(accountLabels, "account")
+
+	seqValueType := prometheus.CounterValue
+	if legacyJszGauges {
+		seqValueType = prometheus.GaugeValue
+	}
+
 	nc := &jszCollector{
+		ctx:          context.Background(),
+		seqValueType: seqValueType,
 		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout: jszRequestTimeout,
 		},
 		// jetstream_stream_total_messages
 		streams: prometheus.NewDesc(
@@ -104,6 +181,48 @@ func newJszCollector(system, endpoint string, servers []*CollectedServer) promet
 			serverLabels,
 			nil,
 		),
+		// jetstream_server_api_total
+		apiTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "server", "api_total"),
+			"Total number of JetStream API requests processed. Counts up monotonically, so use rate() across it",
+			serverLabels,
+			nil,
+		),
+		// jetstream_server_api_errors
+		apiErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "server", "api_errors"),
+			"Total number of JetStream API requests that resulted in an error. Counts up monotonically, so use rate() across it",
+			serverLabels,
+			nil,
+		),
+		// jetstream_server_api_inflight
+		apiInflight: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "server", "api_inflight"),
+			"Number of JetStream API requests currently being processed",
+			serverLabels,
+			nil,
+		),
+		// jetstream_account_api_total
+		accountAPITotal: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "account", "api_total"),
+			"Total number of JetStream API requests processed for an account. Counts up monotonically, so use rate() across it",
+			accountLabels,
+			nil,
+		),
+		// jetstream_account_api_errors
+		accountAPIErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "account", "api_errors"),
+			"Total number of JetStream API requests for an account that resulted in an error. Counts up monotonically, so use rate() across it",
+			accountLabels,
+			nil,
+		),
+		// jetstream_account_api_inflight
+		accountAPIInflight: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "account", "api_inflight"),
+			"Number of JetStream API requests for an account currently being processed",
+			accountLabels,
+			nil,
+		),
 		// jetstream_stream_total_messages
 		streamMessages: prometheus.NewDesc(
 			prometheus.BuildFQName(system, "stream", "total_messages"),
@@ -118,10 +237,17 @@ func newJszCollector(system, endpoint string, servers []*CollectedServer) promet
 			streamLabels,
 			nil,
 		),
+		// jetstream_stream_first_seq
+		streamFirstSeq: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "stream", "first_seq"),
+			"First sequence from a stream. Counts up monotonically as the stream trims, so use rate() across it; see --legacy-jsz-gauges",
+			streamLabels,
+			nil,
+		),
 		// jetstream_stream_state_last_seq
 		streamLastSeq: prometheus.NewDesc(
 			prometheus.BuildFQName(system, "stream", "last_seq"),
-			"Last sequence from a stream",
+			"Last sequence from a stream. Counts up monotonically, so use rate() across it; see --legacy-jsz-gauges",
 			streamLabels,
 			nil,
 		),
@@ -132,17 +258,52 @@ func newJszCollector(system, endpoint string, servers []*CollectedServer) promet
 			streamLabels,
 			nil,
 		),
+		// jetstream_stream_deleted_messages
+		streamDeletedMessages: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "stream", "deleted_messages"),
+			"Number of deleted messages currently tracked in a stream's state",
+			streamLabels,
+			nil,
+		),
+		// jetstream_stream_num_subjects
+		streamNumSubjects: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "stream", "num_subjects"),
+			"Number of distinct subjects currently stored in a stream",
+			streamLabels,
+			nil,
+		),
 		// jetstream_consumer_delivered_consumer_seq
 		consumerDeliveredConsumerSeq: prometheus.NewDesc(
 			prometheus.BuildFQName(system, "consumer", "delivered_consumer_seq"),
-			"Latest sequence number of a stream consumer",
+			"Latest sequence number of a stream consumer. Counts up monotonically, so use rate() across it; see --legacy-jsz-gauges",
 			consumerLabels,
 			nil,
 		),
 		// jetstream_consumer_delivered_stream_seq
 		consumerDeliveredStreamSeq: prometheus.NewDesc(
 			prometheus.BuildFQName(system, "consumer", "delivered_stream_seq"),
-			"Latest sequence number of a stream",
+			"Latest sequence number of a stream. Counts up monotonically, so use rate() across it; see --legacy-jsz-gauges",
+			consumerLabels,
+			nil,
+		),
+		// jetstream_consumer_ack_floor_consumer_seq
+		consumerAckFloorConsumerSeq: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "consumer", "ack_floor_consumer_seq"),
+			"Consumer sequence number below which every message has been acked. Counts up monotonically, so use rate() across it; see --legacy-jsz-gauges",
+			consumerLabels,
+			nil,
+		),
+		// jetstream_consumer_ack_floor_stream_seq
+		consumerAckFloorStreamSeq: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "consumer", "ack_floor_stream_seq"),
+			"Stream sequence number below which every message has been acked. Counts up monotonically, so use rate() across it; see --legacy-jsz-gauges",
+			consumerLabels,
+			nil,
+		),
+		// jetstream_consumer_ack_lag
+		consumerAckLag: prometheus.NewDesc(
+			prometheus.BuildFQName(system, "consumer", "ack_lag"),
+			"Number of delivered stream messages not yet acked (delivered_stream_seq - ack_floor_stream_seq)",
 			consumerLabels,
 			nil,
 		),
@@ -156,7 +317,7 @@ func newJszCollector(system, endpoint string, servers []*CollectedServer) promet
 		// jetstream_consumer_num_redelivered
 		consumerNumRedelivered: prometheus.NewDesc(
 			prometheus.BuildFQName(system, "consumer", "num_redelivered"),
-			"Number of redelivered messages from a consumer",
+			"Number of redelivered messages from a consumer. Counts up monotonically, so use rate() across it; see --legacy-jsz-gauges",
 			consumerLabels,
 			nil,
 		),
@@ -176,15 +337,26 @@ func newJszCollector(system, endpoint string, servers []*CollectedServer) promet
 		),
 	}
 
-	nc.servers = make([]*CollectedServer, len(servers))
+	nc.SetServers(servers)
+
+	return nc
+}
+
+// SetServers implements ServerSink, replacing the set of servers polled on
+// the next Collect call. It is safe to call concurrently with Collect, e.g.
+// from a Discoverer picking up a cluster membership change.
+func (nc *jszCollector) SetServers(servers []*CollectedServer) {
+	updated := make([]*CollectedServer, len(servers))
 	for i, s := range servers {
-		nc.servers[i] = &CollectedServer{
+		updated[i] = &CollectedServer{
 			ID:  s.ID,
 			URL: s.URL + jszSuffix,
 		}
 	}
 
-	return nc
+	nc.Lock()
+	nc.servers = updated
+	nc.Unlock()
 }
 
 // Describe shares the info description from a prometheus metric.
@@ -194,93 +366,198 @@ func (nc *jszCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- nc.consumers
 	ch <- nc.messages
 	ch <- nc.bytes
+	ch <- nc.apiTotal
+	ch <- nc.apiErrors
+	ch <- nc.apiInflight
+	ch <- nc.accountAPITotal
+	ch <- nc.accountAPIErrors
+	ch <- nc.accountAPIInflight
 
 	// Stream state
 	ch <- nc.streamMessages
 	ch <- nc.streamBytes
+	ch <- nc.streamFirstSeq
 	ch <- nc.streamLastSeq
 	ch <- nc.streamConsumerCount
+	ch <- nc.streamDeletedMessages
+	ch <- nc.streamNumSubjects
 
 	// Consumer state
 	ch <- nc.consumerDeliveredConsumerSeq
 	ch <- nc.consumerDeliveredStreamSeq
+	ch <- nc.consumerAckFloorConsumerSeq
+	ch <- nc.consumerAckFloorStreamSeq
+	ch <- nc.consumerAckLag
 	ch <- nc.consumerNumAckPending
 	ch <- nc.consumerNumRedelivered
 	ch <- nc.consumerNumWaiting
 	ch <- nc.consumerNumPending
 }
 
-// Collect gathers the server jsz metrics.
-func (nc *jszCollector) Collect(ch chan<- prometheus.Metric) {
-	for _, server := range nc.servers {
+// SetContext sets the context that bounds the next Collect call, letting the
+// HTTP handler serving /metrics cancel in-flight server fetches if the
+// scrape itself is canceled or times out.
+func (nc *jszCollector) SetContext(ctx context.Context) {
+	nc.Lock()
+	nc.ctx = ctx
+	nc.Unlock()
+}
+
+// fetchJsz fetches a single server's /jsz payload, deduplicating concurrent
+// fetches of the same URL so overlapping scrapes share one upstream request.
+func (nc *jszCollector) fetchJsz(ctx context.Context, server *CollectedServer) (*nats.JSInfo, error) {
+	v, err, _ := nc.group.Do(server.URL, func() (interface{}, error) {
+		reqCtx, cancel := context.WithTimeout(ctx, nc.httpClient.Timeout)
+		defer cancel()
+
 		var resp nats.JSInfo
-		if err := getMetricURL(nc.httpClient, server.URL, &resp); err != nil {
-			Debugf("ignoring server %s: %v", server.ID, err)
-			continue
+		if err := getMetricURLWithContext(reqCtx, nc.httpClient, server.URL, &resp); err != nil {
+			return nil, err
 		}
+		return &resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*nats.JSInfo), nil
+}
 
-		// JetStream Server Metrics
-		var serverID, clusterName, jsDomain, clusterLeader string
-		var streamName, streamLeader string
-		var consumerName, consumerLeader string
+// Collect gathers the server jsz metrics, fanning out one goroutine per
+// server with at most jszMaxConcurrentRequests fetches in flight at once.
+func (nc *jszCollector) Collect(ch chan<- prometheus.Metric) {
+	nc.Lock()
+	ctx := nc.ctx
+	servers := nc.servers
+	nc.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-		serverID = server.ID
-		if resp.Meta != nil {
-			clusterName = resp.Meta.Name
-			clusterLeader = resp.Meta.Leader
+	sem := make(chan struct{}, jszMaxConcurrentRequests)
+	var wg sync.WaitGroup
+	for _, server := range servers {
+		if ctx.Err() != nil {
+			break
 		}
-		jsDomain = resp.Config.Domain
 
-		serverMetric := func(key *prometheus.Desc, value float64) prometheus.Metric {
-			return prometheus.MustNewConstMetric(key, prometheus.GaugeValue, value,
-				serverID, clusterName, jsDomain, clusterLeader)
-		}
-		ch <- serverMetric(nc.streams, float64(resp.Streams))
-		ch <- serverMetric(nc.consumers, float64(resp.Consumers))
-		ch <- serverMetric(nc.messages, float64(resp.Messages))
-		ch <- serverMetric(nc.bytes, float64(resp.Bytes))
-
-		for _, account := range resp.AccountDetails {
-			for _, stream := range account.Streams {
-				streamName = stream.Name
-				if stream.Cluster != nil {
-					streamLeader = stream.Cluster.Leader
+		wg.Add(1)
+		go func(server *CollectedServer) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			resp, err := nc.fetchJsz(ctx, server)
+			if err != nil {
+				Debugf("ignoring server %s: %v", server.ID, err)
+				return
+			}
+			nc.writeMetrics(ch, server, resp)
+		}(server)
+	}
+	wg.Wait()
+}
+
+// writeMetrics converts a single server's JSInfo into jsz metrics and sends
+// them to ch. It may be called concurrently for different servers.
+func (nc *jszCollector) writeMetrics(ch chan<- prometheus.Metric, server *CollectedServer, resp *nats.JSInfo) {
+	// JetStream Server Metrics
+	var clusterName, jsDomain, clusterLeader string
+	var streamName, streamLeader string
+	var consumerName, consumerLeader, deliverSubject string
+
+	serverID := server.ID
+	if resp.Meta != nil {
+		clusterName = resp.Meta.Name
+		clusterLeader = resp.Meta.Leader
+	}
+	jsDomain = resp.Config.Domain
+
+	serverMetric := func(key *prometheus.Desc, valueType prometheus.ValueType, value float64) prometheus.Metric {
+		return prometheus.MustNewConstMetric(key, valueType, value,
+			serverID, clusterName, jsDomain, clusterLeader)
+	}
+	ch <- serverMetric(nc.streams, prometheus.GaugeValue, float64(resp.Streams))
+	ch <- serverMetric(nc.consumers, prometheus.GaugeValue, float64(resp.Consumers))
+	ch <- serverMetric(nc.messages, prometheus.GaugeValue, float64(resp.Messages))
+	ch <- serverMetric(nc.bytes, prometheus.GaugeValue, float64(resp.Bytes))
+	// API is a JetStreamAPIStats value (embedded via JetStreamStats), not a
+	// pointer, so it's always safe to read directly - no nil check applies.
+	// Total/Errors are lifetime counters; Inflight is a point-in-time gauge.
+	ch <- serverMetric(nc.apiTotal, prometheus.CounterValue, float64(resp.API.Total))
+	ch <- serverMetric(nc.apiErrors, prometheus.CounterValue, float64(resp.API.Errors))
+	ch <- serverMetric(nc.apiInflight, prometheus.GaugeValue, float64(resp.API.Inflight))
+
+	for _, account := range resp.AccountDetails {
+		// account.API is likewise a value field; same reasoning as resp.API above.
+		ch <- prometheus.MustNewConstMetric(nc.accountAPITotal, prometheus.CounterValue, float64(account.API.Total),
+			serverID, clusterName, jsDomain, clusterLeader, account.Name)
+		ch <- prometheus.MustNewConstMetric(nc.accountAPIErrors, prometheus.CounterValue, float64(account.API.Errors),
+			serverID, clusterName, jsDomain, clusterLeader, account.Name)
+		ch <- prometheus.MustNewConstMetric(nc.accountAPIInflight, prometheus.GaugeValue, float64(account.API.Inflight),
+			serverID, clusterName, jsDomain, clusterLeader, account.Name)
+
+		for _, stream := range account.Streams {
+			streamName = stream.Name
+			if stream.Cluster != nil {
+				streamLeader = stream.Cluster.Leader
+			}
+			streamMetric := func(key *prometheus.Desc, valueType prometheus.ValueType, value float64) prometheus.Metric {
+				return prometheus.MustNewConstMetric(key, valueType, value,
+					// Server Labels
+					serverID, clusterName, jsDomain, clusterLeader,
+					// Stream Labels
+					streamName, streamLeader)
+			}
+			ch <- streamMetric(nc.streamMessages, prometheus.GaugeValue, float64(stream.State.Msgs))
+			ch <- streamMetric(nc.streamBytes, prometheus.GaugeValue, float64(stream.State.Bytes))
+			ch <- streamMetric(nc.streamFirstSeq, nc.seqValueType, float64(stream.State.FirstSeq))
+			ch <- streamMetric(nc.streamLastSeq, nc.seqValueType, float64(stream.State.LastSeq))
+			ch <- streamMetric(nc.streamConsumerCount, prometheus.GaugeValue, float64(stream.State.Consumers))
+			ch <- streamMetric(nc.streamDeletedMessages, prometheus.GaugeValue, float64(stream.State.NumDeleted))
+			ch <- streamMetric(nc.streamNumSubjects, prometheus.GaugeValue, float64(stream.State.NumSubjects))
+
+			// Now with the consumers.
+			for _, consumer := range stream.Consumer {
+				consumerName = consumer.Name
+				if consumer.Cluster != nil {
+					consumerLeader = consumer.Cluster.Leader
+				}
+				if consumer.Config != nil {
+					deliverSubject = consumer.Config.DeliverSubject
 				}
-				streamMetric := func(key *prometheus.Desc, value float64) prometheus.Metric {
-					return prometheus.MustNewConstMetric(key, prometheus.GaugeValue, value,
+				consumerMetric := func(key *prometheus.Desc, valueType prometheus.ValueType, value float64) prometheus.Metric {
+					return prometheus.MustNewConstMetric(key, valueType, value,
 						// Server Labels
 						serverID, clusterName, jsDomain, clusterLeader,
 						// Stream Labels
-						streamName, streamLeader)
+						streamName, streamLeader,
+						// Consumer Labels
+						consumerName, consumerLeader, deliverSubject,
+					)
 				}
-				ch <- streamMetric(nc.streamMessages, float64(stream.State.Msgs))
-				ch <- streamMetric(nc.streamBytes, float64(stream.State.Bytes))
-				ch <- streamMetric(nc.streamLastSeq, float64(stream.State.LastSeq))
-				ch <- streamMetric(nc.streamConsumerCount, float64(stream.State.Consumers))
-
-				// Now with the consumers.
-				for _, consumer := range stream.Consumer {
-					consumerName = consumer.Name
-					if consumer.Cluster != nil {
-						consumerLeader = consumer.Cluster.Leader
-					}
-					consumerMetric := func(key *prometheus.Desc, value float64) prometheus.Metric {
-						return prometheus.MustNewConstMetric(key, prometheus.GaugeValue, value,
-							// Server Labels
-							serverID, clusterName, jsDomain, clusterLeader,
-							// Stream Labels
-							streamName, streamLeader,
-							// Consumer Labels
-							consumerName, consumerLeader, deliverSubject,
-						)
-					}
-					ch <- consumerMetric(nc.consumerDeliveredConsumerSeq, float64(consumer.Delivered.Consumer))
-					ch <- consumerMetric(nc.consumerDeliveredStreamSeq, float64(consumer.Delivered.Stream))
-					ch <- consumerMetric(nc.consumerNumAckPending, float64(consumer.NumAckPending))
-					ch <- consumerMetric(nc.consumerNumRedelivered, float64(consumer.NumRedelivered))
-					ch <- consumerMetric(nc.consumerNumWaiting, float64(consumer.NumWaiting))
-					ch <- consumerMetric(nc.consumerNumPending, float64(consumer.NumPending))
+				// Delivered/AckFloor are uint64 sequence numbers; AckFloor can
+				// momentarily exceed Delivered across a consumer reset, a
+				// stream purge, or just a read racing a server-side update,
+				// so subtracting as unsigned would underflow to ~1.8e19
+				// instead of reporting a small (or negative-clamped) lag.
+				ackLag := int64(consumer.Delivered.Stream) - int64(consumer.AckFloor.Stream)
+				if ackLag < 0 {
+					ackLag = 0
 				}
+				ch <- consumerMetric(nc.consumerDeliveredConsumerSeq, nc.seqValueType, float64(consumer.Delivered.Consumer))
+				ch <- consumerMetric(nc.consumerDeliveredStreamSeq, nc.seqValueType, float64(consumer.Delivered.Stream))
+				ch <- consumerMetric(nc.consumerAckFloorConsumerSeq, nc.seqValueType, float64(consumer.AckFloor.Consumer))
+				ch <- consumerMetric(nc.consumerAckFloorStreamSeq, nc.seqValueType, float64(consumer.AckFloor.Stream))
+				ch <- consumerMetric(nc.consumerAckLag, prometheus.GaugeValue, float64(ackLag))
+				ch <- consumerMetric(nc.consumerNumAckPending, prometheus.GaugeValue, float64(consumer.NumAckPending))
+				ch <- consumerMetric(nc.consumerNumRedelivered, nc.seqValueType, float64(consumer.NumRedelivered))
+				ch <- consumerMetric(nc.consumerNumWaiting, prometheus.GaugeValue, float64(consumer.NumWaiting))
+				ch <- consumerMetric(nc.consumerNumPending, prometheus.GaugeValue, float64(consumer.NumPending))
 			}
 		}
 	}
@@ -0,0 +1,285 @@
+// Copyright 2021 The NATS Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// TestJszCollectorDedupesConcurrentScrapes starts a slow fake /jsz endpoint
+// and fires several concurrent Collect calls at once, asserting that they
+// are coalesced into a single upstream request via singleflight.
+func TestJszCollectorDedupesConcurrentScrapes(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"streams":1,"consumers":1,"messages":1,"bytes":1}`))
+	}))
+	defer srv.Close()
+
+	servers := []*CollectedServer{
+		{ID: "test-server", URL: srv.URL},
+	}
+	nc := newJszCollector(JetStreamSystem, "/jsz", servers, false).(*jszCollector)
+
+	const concurrentScrapes = 10
+	done := make(chan struct{}, concurrentScrapes)
+	for i := 0; i < concurrentScrapes; i++ {
+		go func() {
+			ch := make(chan prometheus.Metric, 16)
+			go func() {
+				for range ch {
+				}
+			}()
+			nc.Collect(ch)
+			close(ch)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < concurrentScrapes; i++ {
+		<-done
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected singleflight to coalesce into 1 upstream request, got %d", got)
+	}
+}
+
+// jszFixture is a minimal /jsz?consumers=true payload with one stream and
+// one consumer, used to exercise the sequence metrics' value type.
+const jszFixture = `{
+	"streams": 1,
+	"consumers": 1,
+	"api": {"total": 100, "errors": 3, "inflight": 2},
+	"account_details": [{
+		"name": "A",
+		"api": {"total": 50, "errors": 1, "inflight": 1},
+		"stream_detail": [{
+			"name": "orders",
+			"state": {"messages": 10, "bytes": 100, "first_seq": 5, "last_seq": 42, "num_deleted": 2, "num_subjects": 3, "consumer_count": 1},
+			"consumer_detail": [{
+				"stream_name": "orders",
+				"name": "c1",
+				"config": {"deliver_subject": "deliver.c1"},
+				"delivered": {"consumer_seq": 42, "stream_seq": 42},
+				"ack_floor": {"consumer_seq": 40, "stream_seq": 40},
+				"num_redelivered": 7
+			}]
+		}]
+	}]
+}`
+
+// jszUnderflowFixture is identical to jszFixture except its consumer's
+// ack floor is ahead of its delivered sequence, the way a consumer reset or
+// a stream purge can momentarily leave it, used to exercise ack_lag clamping.
+const jszUnderflowFixture = `{
+	"streams": 1,
+	"consumers": 1,
+	"account_details": [{
+		"name": "A",
+		"stream_detail": [{
+			"name": "orders",
+			"state": {"messages": 10, "bytes": 100, "first_seq": 1, "last_seq": 42, "consumer_count": 1},
+			"consumer_detail": [{
+				"stream_name": "orders",
+				"name": "c1",
+				"delivered": {"consumer_seq": 40, "stream_seq": 40},
+				"ack_floor": {"consumer_seq": 42, "stream_seq": 42},
+				"num_redelivered": 0
+			}]
+		}]
+	}]
+}`
+
+// collectMetricByName runs Collect and returns the first metric whose Desc
+// mentions name, failing the test if none is found.
+func collectMetricByName(t *testing.T, nc *jszCollector, name string) *dto.Metric {
+	t.Helper()
+
+	ch := make(chan prometheus.Metric, 64)
+	nc.Collect(ch)
+	close(ch)
+
+	for m := range ch {
+		if !strings.Contains(m.Desc().String(), name) {
+			continue
+		}
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			t.Fatalf("failed to write metric %s: %v", name, err)
+		}
+		return &pb
+	}
+
+	t.Fatalf("no metric matching %q was collected", name)
+	return nil
+}
+
+// TestJszCollectorStreamAndConsumerGauges asserts that the stream
+// point-in-time gauges (deleted_messages, num_subjects) and the first_seq and
+// consumer ack_floor counters are collected from jszFixture with the expected
+// values, and that ack_lag is the straightforward delivered-minus-ack-floor
+// difference when delivered is ahead of the ack floor.
+func TestJszCollectorStreamAndConsumerGauges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jszFixture))
+	}))
+	defer srv.Close()
+
+	servers := []*CollectedServer{{ID: "test-server", URL: srv.URL}}
+	nc := newJszCollector(JetStreamSystem, "/jsz", servers, false).(*jszCollector)
+
+	for _, tc := range []struct {
+		metric    string
+		wantValue float64
+	}{
+		{"stream_deleted_messages", 2},
+		{"stream_num_subjects", 3},
+		{"consumer_ack_lag", 2},
+	} {
+		m := collectMetricByName(t, nc, tc.metric)
+		if got := m.Gauge.GetValue(); m.Gauge == nil || got != tc.wantValue {
+			t.Fatalf("expected %s=%v, got %+v", tc.metric, tc.wantValue, m)
+		}
+	}
+
+	firstSeq := collectMetricByName(t, nc, "stream_first_seq")
+	if firstSeq.Counter == nil || firstSeq.Counter.GetValue() != 5 {
+		t.Fatalf("expected stream_first_seq=5, got %+v", firstSeq)
+	}
+
+	ackFloorStreamSeq := collectMetricByName(t, nc, "consumer_ack_floor_stream_seq")
+	if ackFloorStreamSeq.Counter == nil || ackFloorStreamSeq.Counter.GetValue() != 40 {
+		t.Fatalf("expected consumer_ack_floor_stream_seq=40, got %+v", ackFloorStreamSeq)
+	}
+}
+
+// TestJszCollectorAckLagClampsAtZero asserts that ack_lag never underflows
+// to a huge value when AckFloor is momentarily ahead of Delivered (e.g. a
+// consumer reset or a stream purge racing a scrape), but is clamped at 0.
+func TestJszCollectorAckLagClampsAtZero(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jszUnderflowFixture))
+	}))
+	defer srv.Close()
+
+	servers := []*CollectedServer{{ID: "test-server", URL: srv.URL}}
+	nc := newJszCollector(JetStreamSystem, "/jsz", servers, false).(*jszCollector)
+
+	ackLag := collectMetricByName(t, nc, "consumer_ack_lag")
+	if ackLag.Gauge == nil || ackLag.Gauge.GetValue() != 0 {
+		t.Fatalf("expected consumer_ack_lag to clamp at 0, got %+v", ackLag)
+	}
+}
+
+// TestJszCollectorSeqMetricsAreCounters asserts that, by default, the
+// monotonically increasing sequence metrics are published as counters (so
+// rate() is safe across restarts) rather than the legacy gauges.
+func TestJszCollectorSeqMetricsAreCounters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jszFixture))
+	}))
+	defer srv.Close()
+
+	servers := []*CollectedServer{{ID: "test-server", URL: srv.URL}}
+	nc := newJszCollector(JetStreamSystem, "/jsz", servers, false).(*jszCollector)
+
+	lastSeq := collectMetricByName(t, nc, "stream_last_seq")
+	if lastSeq.Counter == nil || lastSeq.Gauge != nil {
+		t.Fatalf("expected stream_last_seq to be a counter, got %+v", lastSeq)
+	}
+	if got := lastSeq.Counter.GetValue(); got != 42 {
+		t.Fatalf("expected stream_last_seq=42, got %v", got)
+	}
+
+	redelivered := collectMetricByName(t, nc, "num_redelivered")
+	if redelivered.Counter == nil || redelivered.Gauge != nil {
+		t.Fatalf("expected num_redelivered to be a counter, got %+v", redelivered)
+	}
+}
+
+// TestJszCollectorAPIMetricsValueTypes asserts that the JetStream API
+// request/error totals are published as counters (they are lifetime totals
+// from JSInfo.Api, so rate() must work across restarts), while inflight
+// stays a gauge since it is a point-in-time value.
+func TestJszCollectorAPIMetricsValueTypes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jszFixture))
+	}))
+	defer srv.Close()
+
+	servers := []*CollectedServer{{ID: "test-server", URL: srv.URL}}
+	nc := newJszCollector(JetStreamSystem, "/jsz", servers, false).(*jszCollector)
+
+	for _, tc := range []struct {
+		metric    string
+		wantValue float64
+	}{
+		{"server_api_total", 100},
+		{"server_api_errors", 3},
+		{"account_api_total", 50},
+		{"account_api_errors", 1},
+	} {
+		m := collectMetricByName(t, nc, tc.metric)
+		if m.Counter == nil || m.Gauge != nil {
+			t.Fatalf("expected %s to be a counter, got %+v", tc.metric, m)
+		}
+		if got := m.Counter.GetValue(); got != tc.wantValue {
+			t.Fatalf("expected %s=%v, got %v", tc.metric, tc.wantValue, got)
+		}
+	}
+
+	for _, metric := range []string{"server_api_inflight", "account_api_inflight"} {
+		m := collectMetricByName(t, nc, metric)
+		if m.Gauge == nil || m.Counter != nil {
+			t.Fatalf("expected %s to be a gauge, got %+v", metric, m)
+		}
+	}
+}
+
+// TestJszCollectorLegacyGaugesFlag asserts that passing legacyJszGauges
+// (--legacy-jsz-gauges) restores the pre-counter GaugeValue semantics so
+// existing dashboards don't break during the migration window.
+func TestJszCollectorLegacyGaugesFlag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(jszFixture))
+	}))
+	defer srv.Close()
+
+	servers := []*CollectedServer{{ID: "test-server", URL: srv.URL}}
+	nc := newJszCollector(JetStreamSystem, "/jsz", servers, true).(*jszCollector)
+
+	lastSeq := collectMetricByName(t, nc, "stream_last_seq")
+	if lastSeq.Gauge == nil || lastSeq.Counter != nil {
+		t.Fatalf("expected stream_last_seq to be a gauge under --legacy-jsz-gauges, got %+v", lastSeq)
+	}
+	if got := lastSeq.Gauge.GetValue(); got != 42 {
+		t.Fatalf("expected stream_last_seq=42, got %v", got)
+	}
+}